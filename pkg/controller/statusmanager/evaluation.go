@@ -0,0 +1,88 @@
+package statusmanager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// evaluationConditionDetected mirrors the EvaluationConditionsDetected
+// condition other OpenShift operators use to warn about deprecated config
+// ahead of its removal, so admins see the warning in `oc get co` and CVO can
+// gate upgrades on it.
+const evaluationConditionDetected configv1.ClusterStatusConditionType = "EvaluationConditionsDetected"
+
+// evaluationWarning is one caller's current set of deprecated-field
+// warnings, keyed by reason so a later call from the same caller replaces
+// rather than accumulates.
+type evaluationWarning struct {
+	message string
+	fields  []string
+}
+
+// SetEvaluationCondition records that fields are deprecated or scheduled for
+// removal, with reason/message explaining why. Passing an empty fields
+// slice clears any warning previously recorded under reason. Multiple
+// callers are aggregated by reason: the EvaluationConditionsDetected
+// condition lists every field warned about by any caller, deduped, and
+// clears only once no caller has an active warning.
+func (s *StatusManager) SetEvaluationCondition(reason, message string, fields []string) {
+	s.mu.Lock()
+	if len(fields) == 0 {
+		delete(s.evaluationWarnings, reason)
+	} else {
+		s.evaluationWarnings[reason] = evaluationWarning{message: message, fields: fields}
+	}
+	s.mu.Unlock()
+
+	s.enqueueSync()
+}
+
+// evaluationCondition aggregates every registered evaluationWarning into a
+// single EvaluationConditionsDetected condition.
+func (s *StatusManager) evaluationCondition() configv1.ClusterOperatorStatusCondition {
+	s.mu.Lock()
+	warnings := make(map[string]evaluationWarning, len(s.evaluationWarnings))
+	for reason, warning := range s.evaluationWarnings {
+		warnings[reason] = warning
+	}
+	s.mu.Unlock()
+
+	var reasons []string
+	for reason := range warnings {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+
+	seenFields := map[string]bool{}
+	var details []string
+	for _, reason := range reasons {
+		warning := warnings[reason]
+		for _, field := range warning.fields {
+			seenFields[field] = true
+		}
+		details = append(details, fmt.Sprintf("%s: %s (fields: %s)", reason, warning.message, strings.Join(warning.fields, ", ")))
+	}
+
+	if len(seenFields) == 0 {
+		return configv1.ClusterOperatorStatusCondition{
+			Type:   evaluationConditionDetected,
+			Status: configv1.ConditionFalse,
+		}
+	}
+
+	fields := make([]string, 0, len(seenFields))
+	for field := range seenFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return configv1.ClusterOperatorStatusCondition{
+		Type:    evaluationConditionDetected,
+		Status:  configv1.ConditionTrue,
+		Reason:  "DeprecatedConfiguration",
+		Message: fmt.Sprintf("Deprecated fields: %s\n%s", strings.Join(fields, ", "), strings.Join(details, "\n")),
+	}
+}
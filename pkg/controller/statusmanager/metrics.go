@@ -0,0 +1,125 @@
+package statusmanager
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+)
+
+// clusterOperatorDownGracePeriod is how long the operator must have been
+// Degraded or !Available before cluster_operator_up is flipped to 0. A short
+// grace period keeps the gauge from flapping during transient rollouts.
+//
+// Unlike the per-workload rollout timeout and crash-loop grace period, this
+// is intentionally a fixed operator-wide constant rather than an
+// annotation-configurable override: it gates a single cluster_operator_up
+// series for the whole operator, not a specific tracked DaemonSet or
+// Deployment, so there is no natural object to hang a per-workload
+// annotation off of.
+const clusterOperatorDownGracePeriod = 5 * time.Minute
+
+var (
+	// metricClusterOperatorConditions mirrors every condition on the
+	// ClusterOperator object, in the same shape CVO publishes its own
+	// conditions, so alerts can be written without scraping the CR.
+	metricClusterOperatorConditions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cluster_operator_conditions",
+		Help: "Report the conditions of the network cluster operator, mirroring the ClusterOperator object.",
+	}, []string{"name", "condition", "reason"})
+
+	// metricClusterOperatorUp is 0 once the operator has been Degraded or
+	// !Available for longer than clusterOperatorDownGracePeriod, so
+	// ClusterOperatorDown/ClusterOperatorDegraded style alerts can fire
+	// without waiting on the CVO aggregation.
+	metricClusterOperatorUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cluster_operator_up",
+		Help: "Whether the network cluster operator is considered healthy.",
+	}, []string{"name"})
+
+	// metricWorkloadStatus tracks desired/updated/available/unavailable
+	// replica counts for each tracked DaemonSet and Deployment.
+	metricWorkloadStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "network_operator_workload_status",
+		Help: "Desired, updated, available, and unavailable counts for workloads tracked by the network cluster operator.",
+	}, []string{"namespace", "name", "kind", "status"})
+
+	// metricStatusUpdateLatency times how long a single status sync (read
+	// current state, recompute conditions, reconcile the ClusterOperator)
+	// takes.
+	metricStatusUpdateLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "network_operator_status_update_duration_seconds",
+		Help:    "Time taken to recompute and reconcile the network ClusterOperator status.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// metricStatusUpdatesDropped counts status syncs abandoned after
+	// maxStatusSyncRetries failed retries.
+	metricStatusUpdatesDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "network_operator_status_updates_dropped_total",
+		Help: "Number of ClusterOperator status updates dropped after repeated failures.",
+	})
+)
+
+// RegisterMetrics registers the StatusManager's collectors with registry. It
+// should be called once, during operator startup.
+func (s *StatusManager) RegisterMetrics(registry prometheus.Registerer) {
+	registry.MustRegister(
+		metricClusterOperatorConditions,
+		metricClusterOperatorUp,
+		metricWorkloadStatus,
+		metricStatusUpdateLatency,
+		metricStatusUpdatesDropped,
+	)
+}
+
+// updateConditionMetrics mirrors co.Status.Conditions onto
+// metricClusterOperatorConditions and maintains metricClusterOperatorUp,
+// based on how long the operator has been unhealthy.
+func (s *StatusManager) updateConditionMetrics(co *configv1.ClusterOperator) {
+	for _, condition := range co.Status.Conditions {
+		if lastReason, ok := s.lastConditionReason[condition.Type]; ok && lastReason != condition.Reason {
+			metricClusterOperatorConditions.DeleteLabelValues(s.name, string(condition.Type), lastReason)
+		}
+		s.lastConditionReason[condition.Type] = condition.Reason
+
+		metricClusterOperatorConditions.WithLabelValues(s.name, string(condition.Type), condition.Reason).Set(conditionValue(condition.Status))
+	}
+
+	degraded := v1helpers.FindStatusCondition(co.Status.Conditions, configv1.OperatorDegraded)
+	available := v1helpers.FindStatusCondition(co.Status.Conditions, configv1.OperatorAvailable)
+	unhealthy := (degraded != nil && degraded.Status == configv1.ConditionTrue) || (available != nil && available.Status == configv1.ConditionFalse)
+
+	if !unhealthy {
+		s.unhealthySince = time.Time{}
+		metricClusterOperatorUp.WithLabelValues(s.name).Set(1)
+		return
+	}
+
+	if s.unhealthySince.IsZero() {
+		s.unhealthySince = time.Now()
+	}
+	if time.Since(s.unhealthySince) >= clusterOperatorDownGracePeriod {
+		metricClusterOperatorUp.WithLabelValues(s.name).Set(0)
+	} else {
+		metricClusterOperatorUp.WithLabelValues(s.name).Set(1)
+	}
+}
+
+func conditionValue(status configv1.ConditionStatus) float64 {
+	if status == configv1.ConditionTrue {
+		return 1
+	}
+	return 0
+}
+
+// updateWorkloadMetrics records the desired/updated/available/unavailable
+// counts for a single tracked DaemonSet or Deployment.
+func updateWorkloadMetrics(namespace, name, kind string, desired, updated, available, unavailable int32) {
+	metricWorkloadStatus.WithLabelValues(namespace, name, kind, "desired").Set(float64(desired))
+	metricWorkloadStatus.WithLabelValues(namespace, name, kind, "updated").Set(float64(updated))
+	metricWorkloadStatus.WithLabelValues(namespace, name, kind, "available").Set(float64(available))
+	metricWorkloadStatus.WithLabelValues(namespace, name, kind, "unavailable").Set(float64(unavailable))
+}
@@ -0,0 +1,153 @@
+package statusmanager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// defaultCrashLoopGracePeriod is how long a workload may report failing
+	// pods before SetFromPods promotes it from Progressing to Degraded.
+	defaultCrashLoopGracePeriod = 5 * time.Minute
+
+	// crashLoopGracePeriodAnnotation overrides defaultCrashLoopGracePeriod
+	// for a single DaemonSet or Deployment.
+	crashLoopGracePeriodAnnotation = "network.operator.openshift.io/crash-loop-grace-period"
+)
+
+// crashLoopingReasons maps a container waiting reason to the ClusterOperator
+// Degraded reason it should surface as.
+var crashLoopingReasons = map[string]string{
+	"CrashLoopBackOff":           "DeploymentCrashLooping",
+	"ImagePullBackOff":           "ImagePullFailure",
+	"ErrImagePull":               "ImagePullFailure",
+	"CreateContainerConfigError": "ResourceNotFound",
+}
+
+// podFailure describes a single Pod stuck in a crash-looping or
+// image-pull-failing waiting state.
+type podFailure struct {
+	pod    string
+	reason string
+}
+
+// listPods lists the Pods matching selector in namespace, or (nil, nil) if
+// no Pod lister was provided to New().
+func (s *StatusManager) listPods(namespace string, selector labels.Selector) ([]*corev1.Pod, error) {
+	if s.podLister == nil {
+		return nil, nil
+	}
+	return s.podLister.Pods(namespace).List(selector)
+}
+
+// findFailingPods inspects pods and returns one podFailure per container
+// stuck in a known failing waiting state, along with the ClusterOperator
+// Degraded reason of the first such container found.
+func findFailingPods(pods []*corev1.Pod) (string, []podFailure) {
+	coReason := ""
+	var failures []podFailure
+	for _, pod := range pods {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting == nil {
+				continue
+			}
+			reason, known := crashLoopingReasons[cs.State.Waiting.Reason]
+			if !known {
+				continue
+			}
+			failures = append(failures, podFailure{pod: pod.Name, reason: cs.State.Waiting.Reason})
+			if coReason == "" {
+				coReason = reason
+			}
+		}
+	}
+	return coReason, failures
+}
+
+// terminatingPodExcess returns how many Pods matched by a workload's
+// selector are already marked for deletion (DeletionTimestamp set) beyond
+// desiredReplicas. RollingUpdate with maxSurge intentionally creates
+// new-generation Pods before deleting old ones, so a raw count of
+// selector-matched Pods exceeding desiredReplicas is normal mid-surge; only
+// Pods already terminating (commonly still running, e.g. CNI daemons with a
+// long TerminationGracePeriodSeconds) indicate old pods holding onto the
+// node alongside the new generation's.
+func terminatingPodExcess(pods []*corev1.Pod, desiredReplicas int32) int {
+	terminating := 0
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			terminating++
+		}
+	}
+
+	if excess := len(pods) - int(desiredReplicas); excess > 0 && terminating > 0 {
+		if terminating < excess {
+			return terminating
+		}
+		return excess
+	}
+	return 0
+}
+
+// crashLoopPastGracePeriod records the first time name was observed with
+// failing pods and reports whether it has been failing longer than the
+// grace period gracePeriod requests (or defaultCrashLoopGracePeriod if
+// gracePeriod is unset or invalid). Passing failing=false clears any
+// recorded state.
+func (s *StatusManager) crashLoopPastGracePeriod(name types.NamespacedName, failing bool, gracePeriod time.Duration) bool {
+	if !failing {
+		delete(s.crashLoopSince, name)
+		return false
+	}
+
+	if gracePeriod <= 0 {
+		gracePeriod = defaultCrashLoopGracePeriod
+	}
+
+	since, ok := s.crashLoopSince[name]
+	if !ok {
+		since = time.Now()
+		s.crashLoopSince[name] = since
+	}
+	return time.Since(since) >= gracePeriod
+}
+
+// crashLoopGracePeriodFromAnnotations returns the crash-loop grace period a
+// workload's annotations request, or defaultCrashLoopGracePeriod if unset or
+// invalid.
+func crashLoopGracePeriodFromAnnotations(annotations map[string]string) time.Duration {
+	raw, ok := annotations[crashLoopGracePeriodAnnotation]
+	if !ok {
+		return defaultCrashLoopGracePeriod
+	}
+	gracePeriod, err := time.ParseDuration(raw)
+	if err != nil || gracePeriod <= 0 {
+		return defaultCrashLoopGracePeriod
+	}
+	return gracePeriod
+}
+
+// formatPodFailures renders the failing pods for a workload into a single
+// Degraded message listing each pod and its last waiting reason.
+func formatPodFailures(kind, name string, failures []podFailure) string {
+	details := make([]string, 0, len(failures))
+	for _, f := range failures {
+		details = append(details, fmt.Sprintf("%s (%s)", f.pod, f.reason))
+	}
+	return fmt.Sprintf("%s %q has failing pods: %s", kind, name, strings.Join(details, ", "))
+}
+
+func selectorForDaemonSet(ds *appsv1.DaemonSet) (labels.Selector, error) {
+	return metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+}
+
+func selectorForDeployment(dep *appsv1.Deployment) (labels.Selector, error) {
+	return metav1.LabelSelectorAsSelector(dep.Spec.Selector)
+}
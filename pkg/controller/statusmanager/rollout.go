@@ -0,0 +1,65 @@
+package statusmanager
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// defaultRolloutTimeout is how long a tracked workload may stay "not at
+	// level" before it is promoted from Progressing to Degraded.
+	defaultRolloutTimeout = 10 * time.Minute
+
+	// rolloutTimeoutAnnotation overrides defaultRolloutTimeout for a single
+	// DaemonSet or Deployment.
+	rolloutTimeoutAnnotation = "network.operator.openshift.io/rollout-timeout"
+)
+
+// rolloutKey identifies a single rollout of a workload: a new generation
+// always starts a fresh clock, so restarting the operator or rolling out
+// again cleanly resets any previously tracked timeout.
+type rolloutKey struct {
+	name       types.NamespacedName
+	generation int64
+}
+
+// rolloutDeadlineExceeded records the first time name's generation was
+// observed not at level and reports the elapsed time along with whether it
+// has exceeded timeout. Passing atLevel=true clears the tracked state for
+// that generation.
+func (s *StatusManager) rolloutDeadlineExceeded(name types.NamespacedName, generation int64, atLevel bool, timeout time.Duration) (time.Duration, bool) {
+	key := rolloutKey{name: name, generation: generation}
+
+	if atLevel {
+		delete(s.rollouts, key)
+		return 0, false
+	}
+
+	if timeout <= 0 {
+		timeout = defaultRolloutTimeout
+	}
+
+	since, ok := s.rollouts[key]
+	if !ok {
+		since = time.Now()
+		s.rollouts[key] = since
+	}
+
+	elapsed := time.Since(since)
+	return elapsed, elapsed >= timeout
+}
+
+// rolloutTimeoutFromAnnotations returns the rollout timeout a workload's
+// annotations request, or defaultRolloutTimeout if unset or invalid.
+func rolloutTimeoutFromAnnotations(annotations map[string]string) time.Duration {
+	raw, ok := annotations[rolloutTimeoutAnnotation]
+	if !ok {
+		return defaultRolloutTimeout
+	}
+	timeout, err := time.ParseDuration(raw)
+	if err != nil || timeout <= 0 {
+		return defaultRolloutTimeout
+	}
+	return timeout
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ghodss/yaml"
 
@@ -17,6 +19,8 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/util/workqueue"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -30,54 +34,199 @@ const (
 	maxStatusLevel StatusLevel = iota
 )
 
+// statusWorkKey is the single workqueue item used to coalesce status
+// updates: the queue only ever needs to know "something changed", since the
+// desired status is always recomputed from current state on dequeue.
+const statusWorkKey = "status"
+
+// maxStatusSyncRetries bounds how many times a failed status sync is
+// retried (with backoff) before it is dropped and counted in
+// network_operator_status_updates_dropped_total. A dropped sync is not lost
+// forever: resyncPeriod guarantees another attempt.
+const maxStatusSyncRetries = 5
+
+// resyncPeriod is how often the status worker re-syncs even without an
+// explicit enqueueSync call, so an outage that outlasts
+// maxStatusSyncRetries' backoff budget (e.g. the API server being down)
+// still recovers on its own once it's back.
+const resyncPeriod = 2 * time.Minute
+
 // StatusManager coordinates changes to ClusterOperator.Status
 type StatusManager struct {
-	client  client.Client
-	name    string
-	version string
+	client    client.Client
+	podLister corelisters.PodLister
+	name      string
+	version   string
 
-	statusQueue chan Status
+	queue workqueue.RateLimitingInterface
+
+	// mu guards failing, daemonSets, deployments, relatedObjects,
+	// upgradeableCheckers and evaluationWarnings: Set*/Register* setters are
+	// called synchronously from caller (controller) goroutines, while sync()
+	// reads them from the dedicated status worker goroutine. unhealthySince,
+	// crashLoopSince, rollouts and lastConditionReason are only touched from
+	// the status worker goroutine and are used without mu.
+	mu sync.Mutex
 
 	failing [maxStatusLevel]*configv1.ClusterOperatorStatusCondition
 
 	daemonSets     []types.NamespacedName
 	deployments    []types.NamespacedName
 	relatedObjects []configv1.ObjectReference
+
+	// unhealthySince is when the operator most recently became Degraded or
+	// !Available; it is the zero Time while the operator is healthy. Used
+	// to drive the cluster_operator_up metric.
+	unhealthySince time.Time
+
+	// crashLoopSince tracks, per tracked DaemonSet/Deployment, when its pods
+	// were first observed crash-looping or failing to pull their image.
+	crashLoopSince map[types.NamespacedName]time.Time
+
+	// rollouts tracks, per tracked DaemonSet/Deployment generation, when it
+	// was first observed "not at level".
+	rollouts map[rolloutKey]time.Time
+
+	// lastConditionReason tracks, per condition type, the reason most
+	// recently published to metricClusterOperatorConditions, so a changed
+	// reason's stale series can be deleted instead of staying stuck at its
+	// last value forever. Only ever touched from sync()'s call to set() on
+	// the status worker goroutine, so it needs no lock of its own.
+	lastConditionReason map[configv1.ClusterStatusConditionType]string
+
+	upgradeableCheckers []namedUpgradeableChecker
+
+	// evaluationWarnings tracks each caller's (keyed by reason) current set
+	// of deprecated-field warnings, aggregated into
+	// EvaluationConditionsDetected.
+	evaluationWarnings map[string]evaluationWarning
 }
 
+// Status is the desired set of ClusterOperator conditions computed by
+// sync(), ready to be reconciled onto the live object by set().
 type Status struct {
 	conditions            []configv1.ClusterOperatorStatusCondition
 	reachedAvailableLevel bool
 }
 
-func New(client client.Client, name, version string) *StatusManager {
-	statusManager := &StatusManager{client: client, name: name, version: version, statusQueue: make(chan Status, 5)}
-	go statusManager.checkStatus()
+func New(client client.Client, podLister corelisters.PodLister, name, version string) *StatusManager {
+	statusManager := &StatusManager{
+		client:              client,
+		podLister:           podLister,
+		name:                name,
+		version:             version,
+		queue:               workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		crashLoopSince:      map[types.NamespacedName]time.Time{},
+		rollouts:            map[rolloutKey]time.Time{},
+		evaluationWarnings:  map[string]evaluationWarning{},
+		lastConditionReason: map[configv1.ClusterStatusConditionType]string{},
+	}
+	go statusManager.runWorker()
+	go statusManager.runResync()
 	return statusManager
 }
 
-func (s *StatusManager) checkStatus() {
-	for {
-		select {
-		case status := <-s.statusQueue:
-			s.set(status)
+// runResync periodically enqueues a sync regardless of caller activity, so
+// a status sync dropped after maxStatusSyncRetries failed retries is
+// retried once the underlying problem (e.g. the API server being down)
+// clears, instead of waiting indefinitely for an unrelated Set* call.
+func (s *StatusManager) runResync() {
+	ticker := time.NewTicker(resyncPeriod)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.enqueueSync()
+	}
+}
+
+// enqueueSync requests that the ClusterOperator status be recomputed and
+// reconciled. Any number of calls between worker iterations collapse into a
+// single sync of the latest desired state.
+func (s *StatusManager) enqueueSync() {
+	s.queue.Add(statusWorkKey)
+}
+
+func (s *StatusManager) runWorker() {
+	for s.processNextWorkItem() {
+	}
+}
+
+func (s *StatusManager) processNextWorkItem() bool {
+	key, shutdown := s.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer s.queue.Done(key)
+
+	if err := s.sync(); err != nil {
+		if s.queue.NumRequeues(key) < maxStatusSyncRetries {
+			log.Printf("Failed to sync ClusterOperator %q status, retrying: %v", s.name, err)
+			s.queue.AddRateLimited(key)
+			return true
+		}
+		log.Printf("Failed to sync ClusterOperator %q status after %d attempts, dropping: %v", s.name, maxStatusSyncRetries+1, err)
+		metricStatusUpdatesDropped.Inc()
+	}
+
+	s.queue.Forget(key)
+	return true
+}
+
+// sync recomputes the desired ClusterOperator status from current state
+// (tracked DaemonSets/Deployments, failing[], evaluation warnings, and
+// Upgradeable checkers) and reconciles it onto the live object.
+func (s *StatusManager) sync() error {
+	start := time.Now()
+	defer func() { metricStatusUpdateLatency.Observe(time.Since(start).Seconds()) }()
+
+	progressing, reachedAvailableLevel := s.computeWorkloadStatus()
+
+	conditions := append([]configv1.ClusterOperatorStatusCondition{}, progressing...)
+	conditions = append(conditions, s.degradedCondition(), s.evaluationCondition())
+
+	return s.set(Status{conditions: conditions, reachedAvailableLevel: reachedAvailableLevel})
+}
+
+// degradedCondition returns the OperatorDegraded condition for the
+// highest-priority (lowest StatusLevel) active failure in failing[], or
+// Degraded=False if nothing is failing.
+func (s *StatusManager) degradedCondition() configv1.ClusterOperatorStatusCondition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range s.failing {
+		if c != nil {
+			return *c
 		}
 	}
+	return configv1.ClusterOperatorStatusCondition{
+		Type:   configv1.OperatorDegraded,
+		Status: configv1.ConditionFalse,
+	}
+}
+
+// setFailing updates failing[level] under mu; condition may be nil to clear
+// the level.
+func (s *StatusManager) setFailing(level StatusLevel, condition *configv1.ClusterOperatorStatusCondition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing[level] = condition
 }
 
-// Set updates the ClusterOperator.Status with the provided conditions
-func (s *StatusManager) set(status Status) {
+// set reconciles the ClusterOperator object to match status, returning an
+// error so the caller can retry on a conflict or a transient API failure.
+func (s *StatusManager) set(status Status) error {
 
 	co := &configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: s.name}}
 	err := s.client.Get(context.TODO(), types.NamespacedName{Name: s.name}, co)
 	isNotFound := errors.IsNotFound(err)
 	if err != nil && !isNotFound {
-		log.Printf("Failed to get ClusterOperator %q: %v", s.name, err)
-		return
+		return fmt.Errorf("failed to get ClusterOperator %q: %w", s.name, err)
 	}
 
 	oldStatus := co.Status.DeepCopy()
+	s.mu.Lock()
 	co.Status.RelatedObjects = s.relatedObjects
+	s.mu.Unlock()
 
 	if status.reachedAvailableLevel {
 		if releaseVersion := os.Getenv("RELEASE_VERSION"); len(releaseVersion) > 0 {
@@ -105,15 +254,12 @@ func (s *StatusManager) set(status Status) {
 		)
 	}
 
-	v1helpers.SetStatusCondition(&co.Status.Conditions,
-		configv1.ClusterOperatorStatusCondition{
-			Type:   configv1.OperatorUpgradeable,
-			Status: configv1.ConditionTrue,
-		},
-	)
+	v1helpers.SetStatusCondition(&co.Status.Conditions, s.checkUpgradeable(context.TODO()))
+
+	s.updateConditionMetrics(co)
 
 	if reflect.DeepEqual(*oldStatus, co.Status) {
-		return
+		return nil
 	}
 
 	buf, err := yaml.Marshal(co.Status.Conditions)
@@ -122,88 +268,84 @@ func (s *StatusManager) set(status Status) {
 	}
 	if isNotFound {
 		if err := s.client.Create(context.TODO(), co); err != nil {
-			log.Printf("Failed to create ClusterOperator %q: %v", co.Name, err)
-		} else {
-			log.Printf("Created ClusterOperator with conditions:\n%s", string(buf))
+			return fmt.Errorf("failed to create ClusterOperator %q: %w", co.Name, err)
 		}
+		log.Printf("Created ClusterOperator with conditions:\n%s", string(buf))
 	} else {
-		err = s.client.Status().Update(context.TODO(), co)
-		if err != nil {
-			log.Printf("Failed to update ClusterOperator %q: %v", co.Name, err)
-		} else {
-			log.Printf("Updated ClusterOperator with conditions:\n%s", string(buf))
+		if err := s.client.Status().Update(context.TODO(), co); err != nil {
+			return fmt.Errorf("failed to update ClusterOperator %q: %w", co.Name, err)
 		}
+		log.Printf("Updated ClusterOperator with conditions:\n%s", string(buf))
 	}
-}
-
-// syncDegraded syncs the current Degraded status
-func (s *StatusManager) syncDegraded() {
-	for _, c := range s.failing {
-		if c != nil {
-			s.statusQueue <- Status{
-				reachedAvailableLevel: false,
-				conditions: []configv1.ClusterOperatorStatusCondition{
-					*c,
-				},
-			}
-			return
-		}
-	}
-	s.statusQueue <- Status{
-		reachedAvailableLevel: false,
-		conditions: []configv1.ClusterOperatorStatusCondition{
-			configv1.ClusterOperatorStatusCondition{
-				Type:   configv1.OperatorDegraded,
-				Status: configv1.ConditionFalse,
-			},
-		},
-	}
+	return nil
 }
 
 // SetDegraded marks the operator as Degraded with the given reason and message. If it
 // is not already failing for a lower-level reason, the operator's status will be updated.
 func (s *StatusManager) SetDegraded(level StatusLevel, reason, message string) {
-	s.failing[level] = &configv1.ClusterOperatorStatusCondition{
+	s.setFailing(level, &configv1.ClusterOperatorStatusCondition{
 		Type:    configv1.OperatorDegraded,
 		Status:  configv1.ConditionTrue,
 		Reason:  reason,
 		Message: message,
-	}
-	s.syncDegraded()
+	})
+	s.enqueueSync()
 }
 
 // SetNotDegraded marks the operator as not Degraded at the given level. If the operator
 // status previously indicated failure at this level, it will updated to show the next
 // higher-level failure, or else to show that the operator is no longer failing.
 func (s *StatusManager) SetNotDegraded(level StatusLevel) {
-	if s.failing[level] != nil {
-		s.failing[level] = nil
-	}
-	s.syncDegraded()
+	s.setFailing(level, nil)
+	s.enqueueSync()
 }
 
 func (s *StatusManager) SetDaemonSets(daemonSets []types.NamespacedName) {
+	s.mu.Lock()
 	s.daemonSets = daemonSets
+	s.mu.Unlock()
 }
 
 func (s *StatusManager) SetDeployments(deployments []types.NamespacedName) {
+	s.mu.Lock()
 	s.deployments = deployments
+	s.mu.Unlock()
 }
 
 func (s *StatusManager) SetRelatedObjects(relatedObjects []configv1.ObjectReference) {
+	s.mu.Lock()
 	s.relatedObjects = relatedObjects
+	s.mu.Unlock()
 }
 
-// SetFromPods sets the operator Degraded/Progressing/Available status, based on
-// the current status of the manager's DaemonSets and Deployments.
+// SetFromPods requests that the operator's Degraded/Progressing/Available
+// status be recomputed from the current status of the manager's DaemonSets
+// and Deployments. The recomputation itself happens on the status worker, so
+// bursts of calls collapse into a single read-and-update cycle.
 func (s *StatusManager) SetFromPods() {
+	s.enqueueSync()
+}
+
+// computeWorkloadStatus re-reads the manager's tracked DaemonSets and
+// Deployments and returns the Progressing/Available conditions they imply,
+// along with whether every workload has reached the target release level.
+// As a side effect, it updates failing[PodDeployment] to reflect any
+// crash-looping workload or hung rollout it finds.
+func (s *StatusManager) computeWorkloadStatus() ([]configv1.ClusterOperatorStatusCondition, bool) {
+
+	s.mu.Lock()
+	daemonSets := append([]types.NamespacedName{}, s.daemonSets...)
+	deployments := append([]types.NamespacedName{}, s.deployments...)
+	s.mu.Unlock()
 
 	targetLevel := os.Getenv("RELEASE_VERSION")
-	reachedAvailableLevel := (len(s.daemonSets) + len(s.deployments)) > 0
+	reachedAvailableLevel := (len(daemonSets) + len(deployments)) > 0
 
 	progressing := []string{}
+	degradedReason := ""
+	degradedMessage := ""
 
-	for _, dsName := range s.daemonSets {
+	for _, dsName := range daemonSets {
 		ds := &appsv1.DaemonSet{}
 		if err := s.client.Get(context.TODO(), dsName, ds); err != nil {
 			log.Printf("Error getting DaemonSet %q: %v", dsName.String(), err)
@@ -223,12 +365,38 @@ func (s *StatusManager) SetFromPods() {
 			progressing = append(progressing, fmt.Sprintf("DaemonSet %q update is being processed (generation %d, observed generation %d)", dsName.String(), ds.Generation, ds.Status.ObservedGeneration))
 		}
 
-		if !(ds.Generation <= ds.Status.ObservedGeneration && ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled && ds.Status.NumberUnavailable == 0 && ds.Annotations["release.openshift.io/version"] == targetLevel) {
+		dsAtLevel := ds.Generation <= ds.Status.ObservedGeneration && ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled && ds.Status.NumberUnavailable == 0 && ds.Annotations["release.openshift.io/version"] == targetLevel
+		if !dsAtLevel {
 			reachedAvailableLevel = false
 		}
+
+		if elapsed, hung := s.rolloutDeadlineExceeded(dsName, ds.Generation, dsAtLevel, rolloutTimeoutFromAnnotations(ds.Annotations)); hung && degradedReason == "" {
+			degradedReason = "RolloutHung"
+			degradedMessage = fmt.Sprintf("DaemonSet %q rollout (generation %d) has not completed after %s", dsName.String(), ds.Generation, elapsed.Round(time.Second))
+		}
+
+		updateWorkloadMetrics(dsName.Namespace, dsName.Name, "daemonset", ds.Status.DesiredNumberScheduled, ds.Status.UpdatedNumberScheduled, ds.Status.NumberAvailable, ds.Status.NumberUnavailable)
+
+		if selector, err := selectorForDaemonSet(ds); err != nil {
+			log.Printf("Error computing Pod selector for DaemonSet %q: %v", dsName.String(), err)
+		} else if pods, err := s.listPods(dsName.Namespace, selector); err != nil {
+			log.Printf("Error listing Pods for DaemonSet %q: %v", dsName.String(), err)
+		} else {
+			if reason, failures := findFailingPods(pods); len(failures) == 0 {
+				s.crashLoopPastGracePeriod(dsName, false, 0)
+			} else if s.crashLoopPastGracePeriod(dsName, true, crashLoopGracePeriodFromAnnotations(ds.Annotations)) && degradedReason == "" {
+				degradedReason = reason
+				degradedMessage = formatPodFailures("DaemonSet", dsName.String(), failures)
+			}
+
+			if excess := terminatingPodExcess(pods, ds.Status.DesiredNumberScheduled); excess > 0 {
+				progressing = append(progressing, fmt.Sprintf("DaemonSet %q is waiting for %d old pods to terminate", dsName.String(), excess))
+				reachedAvailableLevel = false
+			}
+		}
 	}
 
-	for _, depName := range s.deployments {
+	for _, depName := range deployments {
 		dep := &appsv1.Deployment{}
 		if err := s.client.Get(context.TODO(), depName, dep); err != nil {
 			log.Printf("Error getting Deployment %q: %v", depName.String(), err)
@@ -246,38 +414,71 @@ func (s *StatusManager) SetFromPods() {
 			progressing = append(progressing, fmt.Sprintf("Deployment %q update is being processed (generation %d, observed generation %d)", depName.String(), dep.Generation, dep.Status.ObservedGeneration))
 		}
 
-		if !(dep.Generation <= dep.Status.ObservedGeneration && dep.Status.UpdatedReplicas == dep.Status.Replicas && dep.Status.AvailableReplicas > 0 && dep.Annotations["release.openshift.io/version"] == targetLevel) {
+		depAtLevel := dep.Generation <= dep.Status.ObservedGeneration && dep.Status.UpdatedReplicas == dep.Status.Replicas && dep.Status.AvailableReplicas > 0 && dep.Annotations["release.openshift.io/version"] == targetLevel
+		if !depAtLevel {
 			reachedAvailableLevel = false
 		}
-	}
 
-	s.SetNotDegraded(PodDeployment)
+		if elapsed, hung := s.rolloutDeadlineExceeded(depName, dep.Generation, depAtLevel, rolloutTimeoutFromAnnotations(dep.Annotations)); hung && degradedReason == "" {
+			degradedReason = "RolloutHung"
+			degradedMessage = fmt.Sprintf("Deployment %q rollout (generation %d) has not completed after %s", depName.String(), dep.Generation, elapsed.Round(time.Second))
+		}
+
+		updateWorkloadMetrics(depName.Namespace, depName.Name, "deployment", dep.Status.Replicas, dep.Status.UpdatedReplicas, dep.Status.AvailableReplicas, dep.Status.UnavailableReplicas)
 
-	if len(progressing) > 0 {
-		s.statusQueue <- Status{
-			reachedAvailableLevel: reachedAvailableLevel,
-			conditions: []configv1.ClusterOperatorStatusCondition{
-				configv1.ClusterOperatorStatusCondition{
-					Type:    configv1.OperatorProgressing,
-					Status:  configv1.ConditionTrue,
-					Reason:  "Deploying",
-					Message: strings.Join(progressing, "\n"),
-				},
-			},
+		if selector, err := selectorForDeployment(dep); err != nil {
+			log.Printf("Error computing Pod selector for Deployment %q: %v", depName.String(), err)
+		} else if pods, err := s.listPods(depName.Namespace, selector); err != nil {
+			log.Printf("Error listing Pods for Deployment %q: %v", depName.String(), err)
+		} else {
+			if reason, failures := findFailingPods(pods); len(failures) == 0 {
+				s.crashLoopPastGracePeriod(depName, false, 0)
+			} else if s.crashLoopPastGracePeriod(depName, true, crashLoopGracePeriodFromAnnotations(dep.Annotations)) && degradedReason == "" {
+				degradedReason = reason
+				degradedMessage = formatPodFailures("Deployment", depName.String(), failures)
+			}
+
+			desiredReplicas := int32(1)
+			if dep.Spec.Replicas != nil {
+				desiredReplicas = *dep.Spec.Replicas
+			}
+			if excess := terminatingPodExcess(pods, desiredReplicas); excess > 0 {
+				progressing = append(progressing, fmt.Sprintf("Deployment %q is waiting for %d old pods to terminate", depName.String(), excess))
+				reachedAvailableLevel = false
+			}
 		}
+	}
+
+	if degradedReason != "" {
+		s.setFailing(PodDeployment, &configv1.ClusterOperatorStatusCondition{
+			Type:    configv1.OperatorDegraded,
+			Status:  configv1.ConditionTrue,
+			Reason:  degradedReason,
+			Message: degradedMessage,
+		})
 	} else {
-		s.statusQueue <- Status{
-			reachedAvailableLevel: reachedAvailableLevel,
-			conditions: []configv1.ClusterOperatorStatusCondition{
-				configv1.ClusterOperatorStatusCondition{
-					Type:   configv1.OperatorProgressing,
-					Status: configv1.ConditionFalse,
-				},
-				configv1.ClusterOperatorStatusCondition{
-					Type:   configv1.OperatorAvailable,
-					Status: configv1.ConditionTrue,
-				},
+		s.setFailing(PodDeployment, nil)
+	}
+
+	if len(progressing) > 0 {
+		return []configv1.ClusterOperatorStatusCondition{
+			{
+				Type:    configv1.OperatorProgressing,
+				Status:  configv1.ConditionTrue,
+				Reason:  "Deploying",
+				Message: strings.Join(progressing, "\n"),
 			},
-		}
+		}, reachedAvailableLevel
 	}
+
+	return []configv1.ClusterOperatorStatusCondition{
+		{
+			Type:   configv1.OperatorProgressing,
+			Status: configv1.ConditionFalse,
+		},
+		{
+			Type:   configv1.OperatorAvailable,
+			Status: configv1.ConditionTrue,
+		},
+	}, reachedAvailableLevel
 }
@@ -0,0 +1,171 @@
+package statusmanager
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newWorkloadTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to install appsv1 scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to install corev1 scheme: %v", err)
+	}
+	return scheme
+}
+
+func newTestPodLister(t *testing.T, pods ...*corev1.Pod) corelisters.PodLister {
+	t.Helper()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pod := range pods {
+		if err := indexer.Add(pod); err != nil {
+			t.Fatalf("failed to index pod %q: %v", pod.Name, err)
+		}
+	}
+	return corelisters.NewPodLister(indexer)
+}
+
+func surgingDaemonSetPods(namespace string, n int, terminating bool) []*corev1.Pod {
+	pods := make([]*corev1.Pod, 0, n)
+	for i := 0; i < n; i++ {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("ovnkube-node-%d", i),
+				Namespace: namespace,
+				Labels:    map[string]string{"app": "ovnkube-node"},
+			},
+		}
+		if terminating && i == 0 {
+			now := metav1.Now()
+			pod.DeletionTimestamp = &now
+		}
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+func findCondition(conditions []configv1.ClusterOperatorStatusCondition, conditionType configv1.ClusterStatusConditionType) *configv1.ClusterOperatorStatusCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func newTestDaemonSet(name types.NamespacedName) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "ovnkube-node"}},
+		},
+		Status: appsv1.DaemonSetStatus{
+			DesiredNumberScheduled: 2,
+			UpdatedNumberScheduled: 2,
+			NumberAvailable:        3,
+			NumberUnavailable:      0,
+		},
+	}
+}
+
+// TestComputeWorkloadStatusIgnoresSurgingPods guards against regressing
+// chunk0-3: a maxSurge rollout legitimately has more selector-matched Pods
+// than DesiredNumberScheduled while the new generation comes up, and that
+// alone must not be reported as "waiting for old pods to terminate".
+func TestComputeWorkloadStatusIgnoresSurgingPods(t *testing.T) {
+	dsName := types.NamespacedName{Namespace: "openshift-network", Name: "ovnkube-node"}
+	s := &StatusManager{
+		client:         fake.NewClientBuilder().WithScheme(newWorkloadTestScheme(t)).WithObjects(newTestDaemonSet(dsName)).Build(),
+		podLister:      newTestPodLister(t, surgingDaemonSetPods(dsName.Namespace, 3, false)...),
+		daemonSets:     []types.NamespacedName{dsName},
+		crashLoopSince: map[types.NamespacedName]time.Time{},
+		rollouts:       map[rolloutKey]time.Time{},
+	}
+
+	conditions, _ := s.computeWorkloadStatus()
+
+	progressing := findCondition(conditions, configv1.OperatorProgressing)
+	if progressing == nil {
+		t.Fatalf("expected a Progressing condition, got %+v", conditions)
+	}
+	if progressing.Status == configv1.ConditionTrue {
+		t.Fatalf("surging Pods without a DeletionTimestamp were reported as waiting to terminate: %q", progressing.Message)
+	}
+}
+
+// TestComputeWorkloadStatusReportsTerminatingExcessPods is the flip side of
+// the above: Pods already marked for deletion beyond DesiredNumberScheduled
+// are genuinely old Pods holding onto the node, and should be reported.
+func TestComputeWorkloadStatusReportsTerminatingExcessPods(t *testing.T) {
+	dsName := types.NamespacedName{Namespace: "openshift-network", Name: "ovnkube-node"}
+	s := &StatusManager{
+		client:         fake.NewClientBuilder().WithScheme(newWorkloadTestScheme(t)).WithObjects(newTestDaemonSet(dsName)).Build(),
+		podLister:      newTestPodLister(t, surgingDaemonSetPods(dsName.Namespace, 3, true)...),
+		daemonSets:     []types.NamespacedName{dsName},
+		crashLoopSince: map[types.NamespacedName]time.Time{},
+		rollouts:       map[rolloutKey]time.Time{},
+	}
+
+	conditions, reachedAvailableLevel := s.computeWorkloadStatus()
+
+	progressing := findCondition(conditions, configv1.OperatorProgressing)
+	if progressing == nil || progressing.Status != configv1.ConditionTrue {
+		t.Fatalf("expected a terminating-pod Progressing=True condition, got %+v", conditions)
+	}
+	if reachedAvailableLevel {
+		t.Fatalf("expected reachedAvailableLevel=false while old Pods are still terminating")
+	}
+}
+
+// TestProcessNextWorkItemDropsAfterMaxRetries guards against regressing
+// chunk0-7: a persistently failing sync must be retried up to
+// maxStatusSyncRetries times, then dropped and counted, rather than retried
+// forever or dropped immediately.
+func TestProcessNextWorkItemDropsAfterMaxRetries(t *testing.T) {
+	// A scheme with no types registered makes every client.Get fail with a
+	// non-NotFound error, so sync() fails deterministically every time.
+	s := &StatusManager{
+		client:              fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build(),
+		name:                "test",
+		queue:               workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, 10*time.Millisecond)),
+		crashLoopSince:      map[types.NamespacedName]time.Time{},
+		rollouts:            map[rolloutKey]time.Time{},
+		evaluationWarnings:  map[string]evaluationWarning{},
+		lastConditionReason: map[configv1.ClusterStatusConditionType]string{},
+	}
+
+	before := testutil.ToFloat64(metricStatusUpdatesDropped)
+
+	s.enqueueSync()
+	for i := 0; i < maxStatusSyncRetries+1; i++ {
+		if !s.processNextWorkItem() {
+			t.Fatalf("processNextWorkItem unexpectedly reported shutdown")
+		}
+	}
+
+	if got := testutil.ToFloat64(metricStatusUpdatesDropped) - before; got != 1 {
+		t.Fatalf("expected metricStatusUpdatesDropped to increase by exactly 1, got %v", got)
+	}
+	if s.queue.Len() != 0 {
+		t.Fatalf("expected the item to be dropped rather than requeued, queue len = %d", s.queue.Len())
+	}
+}
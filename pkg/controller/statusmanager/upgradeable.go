@@ -0,0 +1,92 @@
+package statusmanager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// UpgradeableChecker reports whether some precondition required for a safe
+// upgrade currently holds. Implementations are registered with
+// RegisterUpgradeableChecker and consulted every time StatusManager
+// recomputes OperatorUpgradeable.
+type UpgradeableChecker interface {
+	// CheckUpgradeable returns the conditions that should contribute to the
+	// aggregate Upgradeable status. A checker with nothing to report
+	// returns nil.
+	CheckUpgradeable(ctx context.Context) []configv1.ClusterOperatorStatusCondition
+}
+
+// namedUpgradeableChecker pairs a checker with the name its messages are
+// prefixed with when aggregated.
+type namedUpgradeableChecker struct {
+	name    string
+	checker UpgradeableChecker
+}
+
+// RegisterUpgradeableChecker adds c to the set of checkers consulted when
+// computing OperatorUpgradeable. name prefixes c's messages in the
+// aggregated Upgradeable condition.
+func (s *StatusManager) RegisterUpgradeableChecker(name string, c UpgradeableChecker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.upgradeableCheckers = append(s.upgradeableCheckers, namedUpgradeableChecker{name: name, checker: c})
+}
+
+// checkUpgradeable runs every registered checker and aggregates their
+// results into a single OperatorUpgradeable condition: the worst status
+// wins (False beats Unknown beats True) and every contributing checker's
+// message is concatenated, prefixed with its name.
+func (s *StatusManager) checkUpgradeable(ctx context.Context) configv1.ClusterOperatorStatusCondition {
+	s.mu.Lock()
+	checkers := append([]namedUpgradeableChecker{}, s.upgradeableCheckers...)
+	s.mu.Unlock()
+
+	status := configv1.ConditionTrue
+	reason := "AsExpected"
+	var messages []string
+
+	for _, named := range checkers {
+		for _, condition := range named.checker.CheckUpgradeable(ctx) {
+			if condition.Status == configv1.ConditionTrue {
+				continue
+			}
+			if conditionSeverity(condition.Status) > conditionSeverity(status) {
+				status = condition.Status
+				reason = condition.Reason
+			}
+			messages = append(messages, fmt.Sprintf("%s: %s", named.name, condition.Message))
+		}
+	}
+
+	if status == configv1.ConditionTrue {
+		return configv1.ClusterOperatorStatusCondition{
+			Type:   configv1.OperatorUpgradeable,
+			Status: configv1.ConditionTrue,
+		}
+	}
+
+	sort.Strings(messages)
+	return configv1.ClusterOperatorStatusCondition{
+		Type:    configv1.OperatorUpgradeable,
+		Status:  status,
+		Reason:  reason,
+		Message: strings.Join(messages, "\n"),
+	}
+}
+
+// conditionSeverity orders ConditionStatus values so the worst one can win
+// when aggregating multiple checkers.
+func conditionSeverity(status configv1.ConditionStatus) int {
+	switch status {
+	case configv1.ConditionFalse:
+		return 2
+	case configv1.ConditionUnknown:
+		return 1
+	default:
+		return 0
+	}
+}
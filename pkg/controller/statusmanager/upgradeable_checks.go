@@ -0,0 +1,113 @@
+package statusmanager
+
+import (
+	"context"
+	"fmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operv1 "github.com/openshift/api/operator/v1"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// clusterConfigName is the name of the singleton Network.operator.openshift.io
+// and Network.config.openshift.io resources CNO reads and reconciles.
+const clusterConfigName = "cluster"
+
+// networkTypeMigrationChecker blocks upgrades while a network-plugin
+// migration (e.g. OpenShiftSDN -> OVNKubernetes) is in progress: switching
+// plugins mid-upgrade can leave nodes unable to reach the API server.
+type networkTypeMigrationChecker struct {
+	client client.Client
+}
+
+// NewNetworkTypeMigrationChecker returns an UpgradeableChecker that reports
+// Upgradeable=False while Network.Spec.Migration.NetworkType is set.
+func NewNetworkTypeMigrationChecker(c client.Client) UpgradeableChecker {
+	return &networkTypeMigrationChecker{client: c}
+}
+
+func (c *networkTypeMigrationChecker) CheckUpgradeable(ctx context.Context) []configv1.ClusterOperatorStatusCondition {
+	operConfig := &operv1.Network{}
+	if err := c.client.Get(ctx, types.NamespacedName{Name: clusterConfigName}, operConfig); err != nil {
+		return nil
+	}
+
+	migration := operConfig.Spec.Migration
+	if migration == nil || migration.NetworkType == "" {
+		return nil
+	}
+
+	return []configv1.ClusterOperatorStatusCondition{{
+		Type:    configv1.OperatorUpgradeable,
+		Status:  configv1.ConditionFalse,
+		Reason:  "NetworkTypeMigration",
+		Message: fmt.Sprintf("Migration to network type %q is in progress", migration.NetworkType),
+	}}
+}
+
+// mtuMigrationChecker blocks upgrades while a live MTU migration is in
+// progress: the intermediate dual-MTU state is not one an upgrade should be
+// layered on top of.
+type mtuMigrationChecker struct {
+	client client.Client
+}
+
+// NewMTUMigrationChecker returns an UpgradeableChecker that reports
+// Upgradeable=False while Network.Spec.Migration.MTU is set.
+func NewMTUMigrationChecker(c client.Client) UpgradeableChecker {
+	return &mtuMigrationChecker{client: c}
+}
+
+func (c *mtuMigrationChecker) CheckUpgradeable(ctx context.Context) []configv1.ClusterOperatorStatusCondition {
+	operConfig := &operv1.Network{}
+	if err := c.client.Get(ctx, types.NamespacedName{Name: clusterConfigName}, operConfig); err != nil {
+		return nil
+	}
+
+	if operConfig.Spec.Migration == nil || operConfig.Spec.Migration.MTU == nil {
+		return nil
+	}
+
+	return []configv1.ClusterOperatorStatusCondition{{
+		Type:    configv1.OperatorUpgradeable,
+		Status:  configv1.ConditionFalse,
+		Reason:  "MTUMigration",
+		Message: "An MTU migration is in progress",
+	}}
+}
+
+// configGenerationChecker blocks upgrades while the operator has not yet
+// observed the latest generation of its own config, since that means a
+// reconcile triggered by the most recent config change is still pending.
+type configGenerationChecker struct {
+	client client.Client
+}
+
+// NewConfigGenerationChecker returns an UpgradeableChecker that reports
+// Upgradeable=False while Network.Status.ObservedGeneration lags
+// Network.Generation.
+func NewConfigGenerationChecker(c client.Client) UpgradeableChecker {
+	return &configGenerationChecker{client: c}
+}
+
+func (c *configGenerationChecker) CheckUpgradeable(ctx context.Context) []configv1.ClusterOperatorStatusCondition {
+	operConfig := &operv1.Network{}
+	if err := c.client.Get(ctx, types.NamespacedName{Name: clusterConfigName}, operConfig); err != nil {
+		return nil
+	}
+
+	if operConfig.Generation == operConfig.Status.ObservedGeneration {
+		return nil
+	}
+
+	return []configv1.ClusterOperatorStatusCondition{{
+		Type:   configv1.OperatorUpgradeable,
+		Status: configv1.ConditionFalse,
+		Reason: "ConfigGenerationMismatch",
+		Message: fmt.Sprintf("Network.operator.openshift.io/cluster generation %d has not yet been observed (observed generation %d)",
+			operConfig.Generation, operConfig.Status.ObservedGeneration),
+	}}
+}
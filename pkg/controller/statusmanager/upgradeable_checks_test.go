@@ -0,0 +1,186 @@
+package statusmanager
+
+import (
+	"context"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operv1 "github.com/openshift/api/operator/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newUpgradeableTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := operv1.Install(scheme); err != nil {
+		t.Fatalf("failed to install operv1 scheme: %v", err)
+	}
+	if err := configv1.Install(scheme); err != nil {
+		t.Fatalf("failed to install configv1 scheme: %v", err)
+	}
+	return scheme
+}
+
+func assertUpgradeableFalse(t *testing.T, conditions []configv1.ClusterOperatorStatusCondition, wantReason string) {
+	t.Helper()
+	if len(conditions) != 1 {
+		t.Fatalf("expected exactly one condition, got %+v", conditions)
+	}
+	if conditions[0].Status != configv1.ConditionFalse {
+		t.Fatalf("expected Upgradeable=False, got %+v", conditions[0])
+	}
+	if conditions[0].Reason != wantReason {
+		t.Fatalf("expected reason %q, got %q", wantReason, conditions[0].Reason)
+	}
+}
+
+func TestNetworkTypeMigrationChecker(t *testing.T) {
+	scheme := newUpgradeableTestScheme(t)
+
+	tests := []struct {
+		name    string
+		network *operv1.Network
+	}{
+		{
+			name:    "no Network object",
+			network: nil,
+		},
+		{
+			name: "no migration in progress",
+			network: &operv1.Network{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterConfigName},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tt.network != nil {
+				builder = builder.WithObjects(tt.network)
+			}
+			checker := NewNetworkTypeMigrationChecker(builder.Build())
+
+			if conditions := checker.CheckUpgradeable(context.TODO()); len(conditions) != 0 {
+				t.Fatalf("expected no conditions, got %+v", conditions)
+			}
+		})
+	}
+
+	t.Run("migration in progress", func(t *testing.T) {
+		network := &operv1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterConfigName},
+			Spec: operv1.NetworkSpec{
+				Migration: &operv1.NetworkMigration{NetworkType: string(operv1.NetworkTypeOVNKubernetes)},
+			},
+		}
+		checker := NewNetworkTypeMigrationChecker(fake.NewClientBuilder().WithScheme(scheme).WithObjects(network).Build())
+
+		assertUpgradeableFalse(t, checker.CheckUpgradeable(context.TODO()), "NetworkTypeMigration")
+	})
+}
+
+func TestMTUMigrationChecker(t *testing.T) {
+	scheme := newUpgradeableTestScheme(t)
+
+	tests := []struct {
+		name    string
+		network *operv1.Network
+	}{
+		{
+			name:    "no Network object",
+			network: nil,
+		},
+		{
+			name: "no migration in progress",
+			network: &operv1.Network{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterConfigName},
+			},
+		},
+		{
+			name: "non-MTU migration in progress",
+			network: &operv1.Network{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterConfigName},
+				Spec: operv1.NetworkSpec{
+					Migration: &operv1.NetworkMigration{NetworkType: string(operv1.NetworkTypeOVNKubernetes)},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tt.network != nil {
+				builder = builder.WithObjects(tt.network)
+			}
+			checker := NewMTUMigrationChecker(builder.Build())
+
+			if conditions := checker.CheckUpgradeable(context.TODO()); len(conditions) != 0 {
+				t.Fatalf("expected no conditions, got %+v", conditions)
+			}
+		})
+	}
+
+	t.Run("MTU migration in progress", func(t *testing.T) {
+		mtu := uint32(1500)
+		network := &operv1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterConfigName},
+			Spec: operv1.NetworkSpec{
+				Migration: &operv1.NetworkMigration{MTU: &operv1.MTUMigration{Network: &operv1.MTUMigrationValues{To: &mtu}}},
+			},
+		}
+		checker := NewMTUMigrationChecker(fake.NewClientBuilder().WithScheme(scheme).WithObjects(network).Build())
+
+		assertUpgradeableFalse(t, checker.CheckUpgradeable(context.TODO()), "MTUMigration")
+	})
+}
+
+func TestConfigGenerationChecker(t *testing.T) {
+	scheme := newUpgradeableTestScheme(t)
+
+	tests := []struct {
+		name    string
+		network *operv1.Network
+	}{
+		{
+			name:    "no Network object",
+			network: nil,
+		},
+		{
+			name: "observed generation matches",
+			network: &operv1.Network{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterConfigName, Generation: 2},
+				Status:     operv1.NetworkStatus{OperatorStatus: operv1.OperatorStatus{ObservedGeneration: 2}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			if tt.network != nil {
+				builder = builder.WithObjects(tt.network)
+			}
+			checker := NewConfigGenerationChecker(builder.Build())
+
+			if conditions := checker.CheckUpgradeable(context.TODO()); len(conditions) != 0 {
+				t.Fatalf("expected no conditions, got %+v", conditions)
+			}
+		})
+	}
+
+	t.Run("observed generation lags", func(t *testing.T) {
+		network := &operv1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterConfigName, Generation: 3},
+			Status:     operv1.NetworkStatus{OperatorStatus: operv1.OperatorStatus{ObservedGeneration: 2}},
+		}
+		checker := NewConfigGenerationChecker(fake.NewClientBuilder().WithScheme(scheme).WithObjects(network).Build())
+
+		assertUpgradeableFalse(t, checker.CheckUpgradeable(context.TODO()), "ConfigGenerationMismatch")
+	})
+}